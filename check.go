@@ -0,0 +1,150 @@
+package go2oapi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// APIDiff describes how one function's schema changed between two parses of
+// a package, as reported by the `go2oapi check` subcommand.
+type APIDiff struct {
+	// Function is the name of the function this diff covers.
+	Function string `json:"function"`
+	// RemovedFunction reports that the function existed in the old schema
+	// but no longer does; always Breaking.
+	RemovedFunction bool `json:"removedFunction,omitempty"`
+	// AddedParams lists parameters present now but not in the old schema.
+	AddedParams []string `json:"addedParams,omitempty"`
+	// AddedRequiredParams is the subset of AddedParams that are required,
+	// which breaks existing callers but is common enough during active
+	// development that callers may opt to allow it (see -allow_new).
+	AddedRequiredParams []string `json:"addedRequiredParams,omitempty"`
+	// RemovedParams lists parameters present in the old schema but no
+	// longer present.
+	RemovedParams []string `json:"removedParams,omitempty"`
+	// NewlyRequired lists parameters that existed before as optional and
+	// are now required.
+	NewlyRequired []string `json:"newlyRequired,omitempty"`
+	// TypeChanges describes parameters whose type changed, formatted as
+	// "name: oldType -> newType".
+	TypeChanges []string `json:"typeChanges,omitempty"`
+	// TightenedEnums lists parameters whose enum of allowed values shrank.
+	TightenedEnums []string `json:"tightenedEnums,omitempty"`
+	// Breaking reports whether this diff, on its own, breaks existing
+	// callers. It does not account for AddedRequiredParams, which a caller
+	// may choose to allow via policy (see -allow_new).
+	Breaking bool `json:"breaking"`
+}
+
+func (d APIDiff) hasChanges() bool {
+	return d.RemovedFunction || d.Breaking || len(d.AddedParams) > 0 || len(d.AddedRequiredParams) > 0
+}
+
+// CompareFunctions diffs old against current, returning one APIDiff per
+// function whose schema changed. A function missing from current is
+// reported as RemovedFunction; one present only in current is not reported
+// at all, since a brand new function can't break an existing caller.
+func CompareFunctions(old, current []*FunctionDetails) []APIDiff {
+	oldByName := map[string]*FunctionDetails{}
+	for _, fn := range old {
+		oldByName[fn.Name] = fn
+	}
+	currentByName := map[string]*FunctionDetails{}
+	for _, fn := range current {
+		currentByName[fn.Name] = fn
+	}
+
+	var diffs []APIDiff
+	for name, oldFn := range oldByName {
+		newFn, ok := currentByName[name]
+		if !ok {
+			diffs = append(diffs, APIDiff{Function: name, RemovedFunction: true, Breaking: true})
+			continue
+		}
+		if d := compareParameters(name, oldFn.Parameters, newFn.Parameters); d.hasChanges() {
+			diffs = append(diffs, d)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Function < diffs[j].Function })
+	return diffs
+}
+
+// compareParameters diffs a function's parameter schema at the top level
+// only (one entry per parameter, matching how ParseFunction builds
+// Parameters.Properties), rather than recursing into nested struct fields.
+func compareParameters(fnName string, oldDef, newDef *Definition) APIDiff {
+	diff := APIDiff{Function: fnName}
+
+	oldProps, oldRequired := propertiesAndRequired(oldDef)
+	newProps, newRequired := propertiesAndRequired(newDef)
+
+	for name, oldProp := range oldProps {
+		newProp, ok := newProps[name]
+		if !ok {
+			diff.RemovedParams = append(diff.RemovedParams, name)
+			continue
+		}
+		if oldProp.Type != "" && newProp.Type != "" && oldProp.Type != newProp.Type {
+			diff.TypeChanges = append(diff.TypeChanges, fmt.Sprintf("%s: %s -> %s", name, oldProp.Type, newProp.Type))
+		}
+		if enumTightened(oldProp.Enum, newProp.Enum) {
+			diff.TightenedEnums = append(diff.TightenedEnums, name)
+		}
+		if !oldRequired[name] && newRequired[name] {
+			diff.NewlyRequired = append(diff.NewlyRequired, name)
+		}
+	}
+	for name := range newProps {
+		if _, ok := oldProps[name]; ok {
+			continue
+		}
+		diff.AddedParams = append(diff.AddedParams, name)
+		if newRequired[name] {
+			diff.AddedRequiredParams = append(diff.AddedRequiredParams, name)
+		}
+	}
+
+	sort.Strings(diff.AddedParams)
+	sort.Strings(diff.AddedRequiredParams)
+	sort.Strings(diff.RemovedParams)
+	sort.Strings(diff.NewlyRequired)
+	sort.Strings(diff.TypeChanges)
+	sort.Strings(diff.TightenedEnums)
+
+	diff.Breaking = len(diff.RemovedParams) > 0 || len(diff.NewlyRequired) > 0 ||
+		len(diff.TypeChanges) > 0 || len(diff.TightenedEnums) > 0
+	return diff
+}
+
+func propertiesAndRequired(d *Definition) (map[string]*Definition, map[string]bool) {
+	if d == nil {
+		return nil, nil
+	}
+	required := map[string]bool{}
+	for _, name := range d.Required {
+		required[name] = true
+	}
+	return d.Properties, required
+}
+
+// enumTightened reports whether newEnum disallows a value oldEnum used to
+// permit, including the case where a previously unrestricted value became
+// restricted to an enum at all.
+func enumTightened(oldEnum, newEnum []string) bool {
+	if newEnum == nil {
+		return false
+	}
+	if len(oldEnum) == 0 {
+		return len(newEnum) > 0
+	}
+	newSet := make(map[string]bool, len(newEnum))
+	for _, v := range newEnum {
+		newSet[v] = true
+	}
+	for _, v := range oldEnum {
+		if !newSet[v] {
+			return true
+		}
+	}
+	return false
+}
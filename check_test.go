@@ -0,0 +1,126 @@
+package go2oapi_test
+
+import (
+	"testing"
+
+	"github.com/tmc/go2oapi"
+)
+
+func def(required []string, props map[string]*go2oapi.Definition) *go2oapi.Definition {
+	return &go2oapi.Definition{Type: go2oapi.Object, Properties: props, Required: required}
+}
+
+func TestCompareFunctions(t *testing.T) {
+	old := []*go2oapi.FunctionDetails{
+		{
+			Name: "Untouched",
+			Parameters: def([]string{"a"}, map[string]*go2oapi.Definition{
+				"a": {Type: go2oapi.String},
+			}),
+		},
+		{
+			Name: "Removed",
+			Parameters: def(nil, map[string]*go2oapi.Definition{
+				"a": {Type: go2oapi.String},
+			}),
+		},
+		{
+			Name: "Changed",
+			Parameters: def([]string{"a", "b"}, map[string]*go2oapi.Definition{
+				"a": {Type: go2oapi.String},
+				"b": {Type: go2oapi.Integer},
+				"c": {Type: go2oapi.String, Enum: []string{"x", "y", "z"}},
+			}),
+		},
+	}
+
+	current := []*go2oapi.FunctionDetails{
+		{
+			Name: "Untouched",
+			Parameters: def([]string{"a"}, map[string]*go2oapi.Definition{
+				"a": {Type: go2oapi.String},
+			}),
+		},
+		{
+			Name: "Changed",
+			Parameters: def([]string{"a", "d"}, map[string]*go2oapi.Definition{
+				"a": {Type: go2oapi.Integer},                     // type change
+				"b": {Type: go2oapi.Integer},                     // no longer required
+				"c": {Type: go2oapi.String, Enum: []string{"x"}}, // tightened enum
+				"d": {Type: go2oapi.Boolean},                     // newly added, required
+			}),
+		},
+		{
+			Name: "Added",
+			Parameters: def(nil, map[string]*go2oapi.Definition{
+				"a": {Type: go2oapi.String},
+			}),
+		},
+	}
+
+	diffs := go2oapi.CompareFunctions(old, current)
+
+	byName := map[string]go2oapi.APIDiff{}
+	for _, d := range diffs {
+		byName[d.Function] = d
+	}
+
+	if _, ok := byName["Untouched"]; ok {
+		t.Errorf("Untouched reported a diff: %+v", byName["Untouched"])
+	}
+	if _, ok := byName["Added"]; ok {
+		t.Error("a brand new function was reported as a diff")
+	}
+
+	removed, ok := byName["Removed"]
+	if !ok {
+		t.Fatal("Removed: expected a diff")
+	}
+	if !removed.RemovedFunction || !removed.Breaking {
+		t.Errorf("Removed: got %+v, want RemovedFunction and Breaking", removed)
+	}
+
+	changed, ok := byName["Changed"]
+	if !ok {
+		t.Fatal("Changed: expected a diff")
+	}
+	if !changed.Breaking {
+		t.Errorf("Changed: Breaking = false, want true")
+	}
+	if len(changed.TypeChanges) != 1 || changed.TypeChanges[0] != "a: string -> integer" {
+		t.Errorf("Changed.TypeChanges = %v, want [\"a: string -> integer\"]", changed.TypeChanges)
+	}
+	if len(changed.TightenedEnums) != 1 || changed.TightenedEnums[0] != "c" {
+		t.Errorf("Changed.TightenedEnums = %v, want [\"c\"]", changed.TightenedEnums)
+	}
+	if len(changed.AddedParams) != 1 || changed.AddedParams[0] != "d" {
+		t.Errorf("Changed.AddedParams = %v, want [\"d\"]", changed.AddedParams)
+	}
+	if len(changed.AddedRequiredParams) != 1 || changed.AddedRequiredParams[0] != "d" {
+		t.Errorf("Changed.AddedRequiredParams = %v, want [\"d\"]", changed.AddedRequiredParams)
+	}
+	if len(changed.NewlyRequired) != 0 {
+		t.Errorf("Changed.NewlyRequired = %v, want none (b went from required to optional, not the reverse)", changed.NewlyRequired)
+	}
+}
+
+func TestCompareFunctionsNewlyRequired(t *testing.T) {
+	old := []*go2oapi.FunctionDetails{
+		{Name: "F", Parameters: def(nil, map[string]*go2oapi.Definition{"a": {Type: go2oapi.String}})},
+	}
+	current := []*go2oapi.FunctionDetails{
+		{Name: "F", Parameters: def([]string{"a"}, map[string]*go2oapi.Definition{"a": {Type: go2oapi.String}})},
+	}
+
+	diffs := go2oapi.CompareFunctions(old, current)
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1: %+v", len(diffs), diffs)
+	}
+	d := diffs[0]
+	if len(d.NewlyRequired) != 1 || d.NewlyRequired[0] != "a" {
+		t.Errorf("NewlyRequired = %v, want [\"a\"]", d.NewlyRequired)
+	}
+	if !d.Breaking {
+		t.Error("Breaking = false, want true for a parameter that became required")
+	}
+}
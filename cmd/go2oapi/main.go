@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/tmc/go2oapi"
 )
@@ -15,11 +16,43 @@ var (
 	srcDir      = flag.String("src", ".", "The directory to scan for Go source files.")
 	handlerFunc = flag.String("func", "", "The name of the function to generate OpenAPI definitions for.")
 	outputFile  = flag.String("output", "-", "The output file where the function details will be written.")
+	typeName    = flag.String("type", "", "Function to generate a schema for as a sibling <file>_oapi.go, go:generate style. Takes precedence over -func.")
+	registry    = flag.String("registry", "", "With -type, an \"<import path>.<FuncName>\" registration function the generated file calls from an init(), instead of go2oapi.Register.")
+	allFuncs    = flag.Bool("all", false, "Generate a full OpenAPI 3.1 document covering every exported function in -src, instead of a single function's parameters.")
+	apiTitle    = flag.String("title", "", "With -all, the OpenAPI document's info.title.")
+	apiVersion  = flag.String("api-version", "0.0.1", "With -all, the OpenAPI document's info.version.")
+	listFuncs   = flag.Bool("list", false, "Dump a JSON array of every exported function's schema in -src, the format `go2oapi check -c` compares against.")
 )
 
 func main() {
+	// "go2oapi check ..." is a subcommand with its own flags, so it must be
+	// dispatched before the top-level flag set parses os.Args.
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		if err := runCheck(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Parse the provided flags.
 	flag.Parse()
+
+	if *typeName != "" {
+		runGenerate()
+		return
+	}
+
+	if *allFuncs {
+		runAll()
+		return
+	}
+
+	if *listFuncs {
+		runList()
+		return
+	}
+
 	// Parse the source directory for the specific function.
 	funcDetails, err := go2oapi.ParseFunction(*srcDir, *handlerFunc)
 	if err != nil {
@@ -40,6 +73,131 @@ func main() {
 	}
 }
 
+// runGenerate implements `//go:generate go2oapi -type=FuncName`: it writes
+// a sibling "<file>_oapi.go" file containing FuncName's precomputed schema.
+func runGenerate() {
+	path, err := go2oapi.Generate(go2oapi.GenerateOptions{
+		Dir:        *srcDir,
+		FuncName:   *typeName,
+		Registry:   *registry,
+		SourceFile: os.Getenv("GOFILE"),
+	})
+	if err != nil {
+		fmt.Printf("Error generating schema for %s: %v\n", *typeName, err)
+		os.Exit(1)
+	}
+	fmt.Println(path)
+}
+
+// runAll implements -all: it parses every exported function in -src and
+// writes a single OpenAPI 3.1 document covering all of them.
+func runAll() {
+	fns, err := go2oapi.ParsePackage(*srcDir, nil)
+	if err != nil {
+		fmt.Printf("Error parsing package: %v\n", err)
+		os.Exit(1)
+	}
+	doc, err := go2oapi.BuildOpenAPI(fns, go2oapi.OpenAPIInfo{
+		Title:   *apiTitle,
+		Version: *apiVersion,
+	})
+	if err != nil {
+		fmt.Printf("Error building OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+	if err := outputJSON(doc, *outputFile); err != nil {
+		fmt.Printf("Error writing JSON to file: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runList implements -list: it parses every exported function in -src and
+// writes the JSON array of FunctionDetails that `go2oapi check -c` reads as
+// its prior snapshot.
+func runList() {
+	fns, err := go2oapi.ParsePackage(*srcDir, nil)
+	if err != nil {
+		fmt.Printf("Error parsing package: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(fns, "", "  ")
+	if err != nil {
+		fmt.Printf("Error generating JSON: %v\n", err)
+		os.Exit(1)
+	}
+	if err := outputJSON(data, *outputFile); err != nil {
+		fmt.Printf("Error writing JSON to file: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runCheck implements the `go2oapi check` subcommand: it regenerates
+// schemas for the functions in -src and reports, as JSON, how each one's
+// schema differs from a prior snapshot (see -list). It returns a non-nil
+// error - causing a non-zero exit - when a breaking change is detected that
+// isn't covered by -allow_new or -except.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	oldFile := fs.String("c", "", "Path to a prior schema snapshot, as produced by `go2oapi -list` (required).")
+	src := fs.String("src", ".", "The directory to scan for Go source files.")
+	allowNew := fs.Bool("allow_new", false, "Don't fail when a newly added parameter is required; removed parameters, type changes, tightened enums and existing parameters becoming required still fail.")
+	exceptFile := fs.String("except", "", "Path to a file listing function names (one per line) whose breaking changes should be ignored.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *oldFile == "" {
+		return fmt.Errorf("check: -c is required")
+	}
+
+	oldData, err := os.ReadFile(*oldFile)
+	if err != nil {
+		return fmt.Errorf("check: reading %s: %w", *oldFile, err)
+	}
+	var oldFns []*go2oapi.FunctionDetails
+	if err := json.Unmarshal(oldData, &oldFns); err != nil {
+		return fmt.Errorf("check: parsing %s: %w", *oldFile, err)
+	}
+
+	newFns, err := go2oapi.ParsePackage(*src, nil)
+	if err != nil {
+		return fmt.Errorf("check: parsing %s: %w", *src, err)
+	}
+
+	except := map[string]bool{}
+	if *exceptFile != "" {
+		data, err := os.ReadFile(*exceptFile)
+		if err != nil {
+			return fmt.Errorf("check: reading %s: %w", *exceptFile, err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				except[line] = true
+			}
+		}
+	}
+
+	diffs := go2oapi.CompareFunctions(oldFns, newFns)
+
+	out, err := json.MarshalIndent(diffs, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	for _, d := range diffs {
+		if except[d.Function] {
+			continue
+		}
+		if d.Breaking {
+			return fmt.Errorf("check: breaking API change detected in %s", d.Function)
+		}
+		if len(d.AddedRequiredParams) > 0 && !*allowNew {
+			return fmt.Errorf("check: %s added required parameter(s) %v (pass -allow_new to permit this)", d.Function, d.AddedRequiredParams)
+		}
+	}
+	return nil
+}
+
 // GenerateJSON takes the details of a function and generates a JSON representation.
 func generateJSON(details *go2oapi.FunctionDetails) ([]byte, error) {
 	return json.MarshalIndent(details, "", "  ")
@@ -0,0 +1,137 @@
+package go2oapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// GenerateOptions configures Generate.
+type GenerateOptions struct {
+	// Dir is the package directory to parse, matching ParseFunction's
+	// filePath argument.
+	Dir string
+	// FuncName is the function to generate a schema for, matching the
+	// go:generate convention used by stringer and jsonenums:
+	//   //go:generate go2oapi -type=NewWidgetFactory
+	FuncName string
+	// Registry names an external registration function as
+	// "<import path>.<FuncName>" that the generated file calls from an
+	// init(), instead of the default go2oapi.Register.
+	Registry string
+	// SourceFile is the file the go:generate directive lives in. When run
+	// under `go generate` this should be os.Getenv("GOFILE"); if empty it
+	// is looked up from FuncName's declaration.
+	SourceFile string
+}
+
+// Generate parses FuncName as ParseFunction does and writes a sibling
+// "<file>_oapi.go" file in the same package containing its precomputed
+// schema, so that a binary can ship it without invoking the parser at
+// runtime. It returns the path written.
+func Generate(opts GenerateOptions) (string, error) {
+	details, err := ParseFunction(opts.Dir, opts.FuncName)
+	if err != nil {
+		return "", err
+	}
+
+	pkgName, declFile, err := packageAndFile(opts.Dir, opts.FuncName)
+	if err != nil {
+		return "", err
+	}
+	sourceFile := opts.SourceFile
+	if sourceFile == "" {
+		sourceFile = declFile
+	}
+
+	src, err := renderGenerated(pkgName, details, opts.Registry)
+	if err != nil {
+		return "", err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(sourceFile), ".go")
+	outputPath := filepath.Join(opts.Dir, base+"_oapi.go")
+	if err := os.WriteFile(outputPath, src, 0644); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// packageAndFile reports the package name and source file declaring
+// funcName, used to name the generated sibling file.
+func packageAndFile(dir, funcName string) (pkgName, file string, err error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypesInfo | packages.NeedTypes,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return "", "", err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return "", "", fmt.Errorf("parse error")
+	}
+	for _, p := range pkgs {
+		fn, ok := findFunction(p, funcName)
+		if !ok {
+			continue
+		}
+		pos := p.Fset.Position(fn.Pos())
+		return p.Name, pos.Filename, nil
+	}
+	return "", "", ErrFunctionNotFound
+}
+
+// renderGenerated produces the gofmt'd source of the generated sibling
+// file. The schema itself travels as an embedded JSON blob, decoded once at
+// init time, rather than as a hand-written Go composite literal, so that
+// Definition can keep growing fields without a matching change here.
+func renderGenerated(pkgName string, details *FunctionDetails, registry string) ([]byte, error) {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	registerImport, registerCall, err := registerStatement(details.Name, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by go2oapi -type=%s. DO NOT EDIT.\n\n", details.Name)
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	fmt.Fprintf(&b, "import (\n\t\"encoding/json\"\n\n\t\"github.com/tmc/go2oapi\"\n%s)\n\n", registerImport)
+	fmt.Fprintf(&b, "var %sSchemaJSON = %s\n\n", details.Name, strconv.Quote(string(payload)))
+	fmt.Fprintf(&b, "// %sSchema is the precomputed schema for %s, generated from its\n", details.Name, details.Name)
+	fmt.Fprintf(&b, "// source so callers don't need to parse it at runtime.\n")
+	fmt.Fprintf(&b, "var %sSchema = func() go2oapi.FunctionDetails {\n", details.Name)
+	fmt.Fprintf(&b, "\tvar d go2oapi.FunctionDetails\n")
+	fmt.Fprintf(&b, "\tif err := json.Unmarshal([]byte(%sSchemaJSON), &d); err != nil {\n", details.Name)
+	fmt.Fprintf(&b, "\t\tpanic(err)\n\t}\n\treturn d\n}()\n\n")
+	fmt.Fprintf(&b, "func init() {\n\t%s\n}\n", registerCall)
+
+	return format.Source([]byte(b.String()))
+}
+
+// registerStatement returns the extra import line (if any) and the init()
+// body that registers a generated schema: go2oapi.Register by default, or
+// an external "<import path>.<FuncName>" named by -registry.
+func registerStatement(funcName, registry string) (imp, call string, err error) {
+	if registry == "" {
+		return "", fmt.Sprintf("go2oapi.Register(%s, %sSchema)", strconv.Quote(funcName), funcName), nil
+	}
+	idx := strings.LastIndex(registry, ".")
+	if idx <= 0 || idx == len(registry)-1 {
+		return "", "", fmt.Errorf("-registry must be \"<import path>.<Func>\", got %q", registry)
+	}
+	importPath, fn := registry[:idx], registry[idx+1:]
+	pkgIdent := importPath[strings.LastIndex(importPath, "/")+1:]
+	return fmt.Sprintf("\t%q\n", importPath), fmt.Sprintf("%s.%s(%s, %sSchema)", pkgIdent, fn, strconv.Quote(funcName), funcName), nil
+}
@@ -0,0 +1,61 @@
+package go2oapi_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmc/go2oapi"
+)
+
+func TestGenerateWritesParsableSchema(t *testing.T) {
+	dir := t.TempDir()
+	src := "package sample\n\n" +
+		"// Greet greets name.\n" +
+		"func Greet(name string) string {\n\treturn \"hi \" + name\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "greet.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module sample\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := go2oapi.Generate(go2oapi.GenerateOptions{Dir: dir, FuncName: "Greet", SourceFile: "greet.go"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if filepath.Base(path) != "greet_oapi.go" {
+		t.Errorf("Generate wrote %q, want a file named greet_oapi.go", path)
+	}
+
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading generated file: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), path, out, parser.AllErrors); err != nil {
+		t.Fatalf("generated file is not valid Go: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), `go2oapi.Register("Greet", GreetSchema)`) {
+		t.Errorf("generated file missing default Register call:\n%s", out)
+	}
+}
+
+func TestRegisterLookup(t *testing.T) {
+	details := go2oapi.FunctionDetails{Name: "RegisterLookupExample"}
+	go2oapi.Register("RegisterLookupExample", details)
+
+	got, ok := go2oapi.Lookup("RegisterLookupExample")
+	if !ok {
+		t.Fatal("Lookup: not found after Register")
+	}
+	if got.Name != details.Name {
+		t.Errorf("Lookup: got %+v, want %+v", got, details)
+	}
+
+	if _, ok := go2oapi.Lookup("NeverRegistered"); ok {
+		t.Error("Lookup: found a name that was never registered")
+	}
+}
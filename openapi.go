@@ -0,0 +1,258 @@
+package go2oapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ParsePackage parses every exported, non-method function in the package at
+// dir the same way ParseFunction parses one. filter, if non-nil, is
+// consulted for each candidate function and may reject it by returning
+// false.
+func ParsePackage(dir string, filter func(*ast.FuncDecl) bool) ([]*FunctionDetails, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedFiles | packages.NeedSyntax | packages.NeedTypesInfo |
+			packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("parse error")
+	}
+
+	var all []*FunctionDetails
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || !fn.Name.IsExported() || fn.Recv != nil {
+					continue
+				}
+				if filter != nil && !filter(fn) {
+					continue
+				}
+				details, err := funcDeclToDetails(pkg, fn)
+				if err != nil {
+					return nil, fmt.Errorf("issue parsing function %q: %w", fn.Name.Name, err)
+				}
+				all = append(all, details)
+			}
+		}
+	}
+	return all, nil
+}
+
+// OpenAPIInfo supplies the "info" block of a document built by
+// BuildOpenAPI.
+type OpenAPIInfo struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// openAPIDocument mirrors the subset of the OpenAPI 3.1 object model that
+// BuildOpenAPI populates.
+type openAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       openAPIInfoObject          `json:"info"`
+	Paths      map[string]openAPIPathItem `json:"paths"`
+	Components *openAPIComponents         `json:"components,omitempty"`
+}
+
+type openAPIInfoObject struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIPathItem struct {
+	Post *openAPIOperation `json:"post"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Description string                     `json:"description,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *Definition `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*Definition `json:"schemas,omitempty"`
+}
+
+// BuildOpenAPI assembles a full OpenAPI 3.1 document from a set of parsed
+// functions, mapping each to a "POST /{name}" operation whose request body
+// is the function's parameters schema. Every named struct type is hoisted
+// into a single "components/schemas", whether it's reused across functions,
+// self-referential, or only used once, with every reference pointing there.
+func BuildOpenAPI(fns []*FunctionDetails, info OpenAPIInfo) ([]byte, error) {
+	doc := openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: openAPIInfoObject{
+			Title:       info.Title,
+			Version:     info.Version,
+			Description: info.Description,
+		},
+		Paths: map[string]openAPIPathItem{},
+	}
+	schemas := map[string]*Definition{}
+
+	for _, fn := range fns {
+		fn.Parameters = hoistNamedTypes(fn.Parameters, schemas)
+		fn.Returns = hoistNamedTypes(fn.Returns, schemas)
+
+		op := &openAPIOperation{
+			OperationID: fn.Name,
+			Description: fn.Description,
+			Responses: map[string]openAPIResponse{
+				"200": {Description: "OK"},
+			},
+		}
+		if fn.Parameters != nil {
+			op.RequestBody = &openAPIRequestBody{
+				Content: map[string]openAPIMediaType{
+					"application/json": {Schema: fn.Parameters},
+				},
+			}
+		}
+		if fn.Returns != nil {
+			op.Responses["200"] = openAPIResponse{
+				Description: "OK",
+				Content: map[string]openAPIMediaType{
+					"application/json": {Schema: fn.Returns},
+				},
+			}
+		}
+		doc.Paths["/"+fn.Name] = openAPIPathItem{Post: op}
+	}
+
+	if len(schemas) > 0 {
+		doc.Components = &openAPIComponents{Schemas: schemas}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// hoistNamedTypes walks d's tree, replacing every Definition expanded from a
+// Go named struct type (including d itself) with a "#/components/schemas"
+// $ref, and recording its shape - Type, Properties and Required only - in
+// schemas the first time that type is seen. Because schemas is shared
+// across every function BuildOpenAPI processes, a type used by more than
+// one function is only expanded once. A pre-existing "#/$defs/Name" ref,
+// from a type referenced more than once within a single function, is
+// rewritten to the same components path rather than re-hoisted.
+//
+// A use site can carry its own keywords on top of the named type - a
+// field's description, a pointer's nullable, a struct tag's format or
+// enum - which belong to that occurrence, not the shared shape. When d
+// carries any, the $ref is wrapped in an "allOf" alongside them instead of
+// replacing d outright, so they survive hoisting instead of being
+// discarded.
+func hoistNamedTypes(d *Definition, schemas map[string]*Definition) *Definition {
+	if d == nil {
+		return nil
+	}
+	if strings.HasPrefix(d.Ref, "#/$defs/") {
+		ref := &Definition{Ref: "#/components/schemas/" + strings.TrimPrefix(d.Ref, "#/$defs/")}
+		return wrapRef(ref, d, schemas)
+	}
+	if d.typeKey == "" {
+		harvestDefs(d, schemas)
+		hoistChildren(d, schemas)
+		return d
+	}
+
+	name := d.typeKey
+	if _, exists := schemas[name]; !exists {
+		harvestDefs(d, schemas)
+		canonical := &Definition{Type: d.Type, Properties: d.Properties, Required: d.Required}
+		hoistChildren(canonical, schemas)
+		schemas[name] = canonical
+	}
+	return wrapRef(&Definition{Ref: "#/components/schemas/" + name}, d, schemas)
+}
+
+// wrapRef returns ref as-is, unless d - the Definition ref is replacing -
+// carries schema keywords of its own beyond its struct shape (a field's
+// description, a pointer's nullable, a struct tag's format or enum, and
+// the like). Those keywords belong to this particular use site, not the
+// shared named type, so they'd otherwise be silently discarded when the
+// type is hoisted into a shared $ref. OpenAPI 3.1 permits keywords
+// alongside a bare $ref, but wrapping it in "allOf" is the interoperable
+// way to combine them.
+func wrapRef(ref *Definition, d *Definition, schemas map[string]*Definition) *Definition {
+	if !hasSiblingKeywords(d) {
+		return ref
+	}
+	overlay := *d
+	overlay.typeKey = ""
+	overlay.Type = ""
+	overlay.Properties = nil
+	overlay.Required = nil
+	overlay.Ref = ""
+	overlay.Defs = nil
+	overlay.AllOf = append([]*Definition{ref}, overlay.AllOf...)
+	hoistChildren(&overlay, schemas)
+	return &overlay
+}
+
+// hasSiblingKeywords reports whether d carries any schema keyword beyond
+// its struct shape (Type, Properties, Required) - a field's description, a
+// pointer's nullable, or a struct tag's format, enum, and the like - that a
+// hoisted $ref must preserve at its use site.
+func hasSiblingKeywords(d *Definition) bool {
+	return d.Description != "" || len(d.Enum) > 0 || d.Items != nil || d.AdditionalProperties != nil ||
+		d.Format != "" || d.Nullable || len(d.OneOf) > 0 || len(d.AnyOf) > 0 || len(d.AllOf) > 0 ||
+		d.Minimum != nil || d.Maximum != nil || d.MinLength != nil || d.MaxLength != nil ||
+		d.Pattern != "" || d.Default != nil
+}
+
+// harvestDefs hoists every entry in d.Defs into schemas. A type's only full
+// expansion can end up reachable solely through Defs rather than inline -
+// e.g. a type used once via an embedded field (whose own slot was
+// flattened away into the parent) and again directly elsewhere in the same
+// function - so Defs must be visited explicitly rather than relying on the
+// inline tree walk to reach every named type.
+func harvestDefs(d *Definition, schemas map[string]*Definition) {
+	for _, def := range d.Defs {
+		hoistNamedTypes(def, schemas)
+	}
+	d.Defs = nil
+}
+
+func hoistChildren(d *Definition, schemas map[string]*Definition) {
+	for k, p := range d.Properties {
+		d.Properties[k] = hoistNamedTypes(p, schemas)
+	}
+	d.Items = hoistNamedTypes(d.Items, schemas)
+	d.AdditionalProperties = hoistNamedTypes(d.AdditionalProperties, schemas)
+	for i, s := range d.OneOf {
+		d.OneOf[i] = hoistNamedTypes(s, schemas)
+	}
+	for i, s := range d.AnyOf {
+		d.AnyOf[i] = hoistNamedTypes(s, schemas)
+	}
+	for i, s := range d.AllOf {
+		d.AllOf[i] = hoistNamedTypes(s, schemas)
+	}
+}
@@ -0,0 +1,173 @@
+package go2oapi_test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/tmc/go2oapi"
+)
+
+func TestParsePackage(t *testing.T) {
+	fns, err := go2oapi.ParsePackage("testdata/sample-a", nil)
+	if err != nil {
+		t.Fatalf("ParsePackage: %v", err)
+	}
+
+	var names []string
+	for _, fn := range fns {
+		names = append(names, fn.Name)
+	}
+	sort.Strings(names)
+
+	want := []string{"NewWidgetFactory", "SampleFunction", "SampleFunctionB"}
+	if len(names) != len(want) {
+		t.Fatalf("got functions %v, want %v", names, want)
+	}
+	for i, name := range want {
+		if names[i] != name {
+			t.Errorf("got functions %v, want %v", names, want)
+			break
+		}
+	}
+}
+
+// openAPISchema mirrors just enough of the OpenAPI document shape to assert
+// on BuildOpenAPI's output without depending on its unexported types.
+type openAPISchema struct {
+	Ref         string                   `json:"$ref,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Nullable    bool                     `json:"nullable,omitempty"`
+	AllOf       []openAPISchema          `json:"allOf,omitempty"`
+	Properties  map[string]openAPISchema `json:"properties,omitempty"`
+}
+
+type openAPIOutput struct {
+	Paths map[string]struct {
+		Post struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema openAPISchema `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"post"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+func TestBuildOpenAPISharedTypeDedup(t *testing.T) {
+	fns, err := go2oapi.ParsePackage("testdata/sample-shared", nil)
+	if err != nil {
+		t.Fatalf("ParsePackage: %v", err)
+	}
+
+	data, err := go2oapi.BuildOpenAPI(fns, go2oapi.OpenAPIInfo{Title: "shared", Version: "0.0.1"})
+	if err != nil {
+		t.Fatalf("BuildOpenAPI: %v", err)
+	}
+
+	var doc openAPIOutput
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal document: %v", err)
+	}
+
+	if len(doc.Components.Schemas) != 1 {
+		t.Fatalf("got %d component schemas, want 1 (SharedInput hoisted once): %+v", len(doc.Components.Schemas), doc.Components.Schemas)
+	}
+	if _, ok := doc.Components.Schemas["SharedInput"]; !ok {
+		t.Fatalf("components.schemas missing SharedInput: %+v", doc.Components.Schemas)
+	}
+
+	for _, path := range []string{"/FuncA", "/FuncB"} {
+		p, ok := doc.Paths[path]
+		if !ok {
+			t.Fatalf("missing path %s", path)
+		}
+		schema := p.Post.RequestBody.Content["application/json"].Schema
+		inSchema, ok := schema.Properties["in"]
+		if !ok {
+			t.Fatalf("%s: request body missing property \"in\": %+v", path, schema)
+		}
+		if inSchema.Ref != "#/components/schemas/SharedInput" {
+			t.Errorf("%s: in.$ref = %q, want #/components/schemas/SharedInput", path, inSchema.Ref)
+		}
+	}
+}
+
+// TestBuildOpenAPIEmbeddedAndDirectUse covers a type whose only in-tree use
+// is hidden inside an embedded (and therefore flattened) field, while also
+// being referenced directly elsewhere in the same function: the $ref the
+// direct use gets must still resolve to a components/schemas entry.
+func TestBuildOpenAPIEmbeddedAndDirectUse(t *testing.T) {
+	fns, err := go2oapi.ParsePackage("testdata/sample-embed-shared", nil)
+	if err != nil {
+		t.Fatalf("ParsePackage: %v", err)
+	}
+
+	data, err := go2oapi.BuildOpenAPI(fns, go2oapi.OpenAPIInfo{Title: "embed-shared", Version: "0.0.1"})
+	if err != nil {
+		t.Fatalf("BuildOpenAPI: %v", err)
+	}
+
+	var doc openAPIOutput
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal document: %v", err)
+	}
+
+	schema := doc.Paths["/F"].Post.RequestBody.Content["application/json"].Schema
+	b, ok := schema.Properties["b"]
+	if !ok {
+		t.Fatalf("request body missing property \"b\": %+v", schema)
+	}
+	if b.Ref != "#/components/schemas/Base" {
+		t.Fatalf("b.$ref = %q, want #/components/schemas/Base", b.Ref)
+	}
+	if _, ok := doc.Components.Schemas["Base"]; !ok {
+		t.Errorf("components.schemas missing Base, %q is a dangling ref: %+v", b.Ref, doc.Components.Schemas)
+	}
+}
+
+// TestBuildOpenAPIHoistPreservesSiblingKeywords covers a named type hoisted
+// into components/schemas from two different use sites that each attach
+// their own keywords on top of it - a pointer's nullable, a field's
+// description - which must survive as an "allOf" wrapper around the $ref
+// rather than being discarded or left as siblings of a bare $ref.
+func TestBuildOpenAPIHoistPreservesSiblingKeywords(t *testing.T) {
+	fns, err := go2oapi.ParsePackage("testdata/sample-overlay", nil)
+	if err != nil {
+		t.Fatalf("ParsePackage: %v", err)
+	}
+
+	data, err := go2oapi.BuildOpenAPI(fns, go2oapi.OpenAPIInfo{Title: "overlay", Version: "0.0.1"})
+	if err != nil {
+		t.Fatalf("BuildOpenAPI: %v", err)
+	}
+
+	var doc openAPIOutput
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal document: %v", err)
+	}
+
+	schema := doc.Paths["/F"].Post.RequestBody.Content["application/json"].Schema
+	w, ok := schema.Properties["w"]
+	if !ok {
+		t.Fatalf("request body missing property \"w\": %+v", schema)
+	}
+	if !w.Nullable || len(w.AllOf) != 1 || w.AllOf[0].Ref != "#/components/schemas/Widget" {
+		t.Errorf("w = %+v, want nullable with allOf [#/components/schemas/Widget]", w)
+	}
+
+	holder, ok := doc.Components.Schemas["Holder"]
+	if !ok {
+		t.Fatalf("components.schemas missing Holder: %+v", doc.Components.Schemas)
+	}
+	primary, ok := holder.Properties["Primary"]
+	if !ok {
+		t.Fatalf("Holder missing property \"Primary\": %+v", holder)
+	}
+	if primary.Description != "Primary is the main widget." || len(primary.AllOf) != 1 || primary.AllOf[0].Ref != "#/components/schemas/Widget" {
+		t.Errorf("Primary = %+v, want description preserved with allOf [#/components/schemas/Widget]", primary)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"os"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/structtag"
@@ -21,8 +24,9 @@ func ParseFunction(filePath string, funcName string) (*FunctionDetails, error) {
 	// uninterpreted to packages.Load so that it can interpret them
 	// according to the conventions of the underlying build system.
 	cfg := &packages.Config{
-		Mode: packages.NeedFiles | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedTypes,
-		Dir:  filePath,
+		Mode: packages.NeedFiles | packages.NeedSyntax | packages.NeedTypesInfo |
+			packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir: filePath,
 	}
 	pkgs, err := packages.Load(cfg, ".")
 	if err != nil {
@@ -46,6 +50,12 @@ func ParseFunction(filePath string, funcName string) (*FunctionDetails, error) {
 		return nil, ErrFunctionNotFound
 	}
 
+	return funcDeclToDetails(pkg, fn)
+}
+
+// funcDeclToDetails builds a FunctionDetails for fn, which must belong to
+// pkg. It is shared by ParseFunction and ParsePackage.
+func funcDeclToDetails(pkg *packages.Package, fn *ast.FuncDecl) (*FunctionDetails, error) {
 	funcDetail := &FunctionDetails{
 		Name:        fn.Name.Name,
 		Description: cleanupComment(fn.Doc),
@@ -54,20 +64,103 @@ func ParseFunction(filePath string, funcName string) (*FunctionDetails, error) {
 			Properties: map[string]*Definition{},
 		},
 	}
+	// seen and defs are shared across every parameter and return value so
+	// that a type used more than once (or referencing itself) is only
+	// expanded once.
+	seen := map[types.Type]*Definition{}
+	defs := map[string]*Definition{}
 	if fn.Type.Params != nil {
 		for _, param := range fn.Type.Params.List {
 			name := identsToName(param.Names)
-			pd, err := paramToDetail(pkg, param)
+			pd, required, err := paramToDetail(pkg, param, seen, defs)
 			if err != nil {
 				return nil, fmt.Errorf("issue parsing parameter '%v': %w", name, err)
 			}
 			funcDetail.Parameters.Properties[name] = pd
-			funcDetail.Parameters.Required = append(funcDetail.Parameters.Required, name)
+			if required {
+				funcDetail.Parameters.Required = append(funcDetail.Parameters.Required, name)
+			}
 		}
 	}
+
+	returns, returnsError, err := resultsToReturns(pkg, fn.Type.Results, seen, defs)
+	if err != nil {
+		return nil, fmt.Errorf("issue parsing return values: %w", err)
+	}
+	funcDetail.Returns = returns
+	funcDetail.ReturnsError = returnsError
+
+	if len(defs) > 0 {
+		funcDetail.Parameters.Defs = defs
+	}
 	return funcDetail, nil
 }
 
+// resultsToReturns builds a Returns schema from a function's result list,
+// using the same type walker as parameters. An error result (by the usual
+// Go convention the trailing one, though any position is treated alike) is
+// reported via the second return value rather than being modeled in the
+// schema. A single unnamed non-error result becomes
+// the Definition directly; more than one becomes an object keyed by name,
+// falling back to positional "r0", "r1", ... names for unnamed results.
+func resultsToReturns(pkg *packages.Package, results *ast.FieldList, seen map[types.Type]*Definition, defs map[string]*Definition) (*Definition, bool, error) {
+	if results == nil {
+		return nil, false, nil
+	}
+
+	type result struct {
+		name string
+		expr ast.Expr
+	}
+	var values []result
+	returnsError := false
+	for _, field := range results.List {
+		if isErrorType(pkg, field.Type) {
+			returnsError = true
+			continue
+		}
+		if len(field.Names) == 0 {
+			values = append(values, result{expr: field.Type})
+			continue
+		}
+		for _, n := range field.Names {
+			values = append(values, result{name: n.Name, expr: field.Type})
+		}
+	}
+	if len(values) == 0 {
+		return nil, returnsError, nil
+	}
+	if len(values) == 1 && values[0].name == "" {
+		d, err := typeToDefinition(pkg, pkg.TypesInfo.TypeOf(values[0].expr), values[0].expr, seen, defs)
+		return d, returnsError, err
+	}
+
+	d := &Definition{Type: Object, Properties: map[string]*Definition{}}
+	for i, v := range values {
+		fd, err := typeToDefinition(pkg, pkg.TypesInfo.TypeOf(v.expr), v.expr, seen, defs)
+		if err != nil {
+			return nil, false, err
+		}
+		name := v.name
+		if name == "" {
+			name = fmt.Sprintf("r%d", i)
+		}
+		d.Properties[name] = fd
+		d.Required = append(d.Required, name)
+	}
+	return d, returnsError, nil
+}
+
+// isErrorType reports whether expr's type is the predeclared error
+// interface, the trailing return Go convention uses to signal failure.
+func isErrorType(pkg *packages.Package, expr ast.Expr) bool {
+	typ := pkg.TypesInfo.TypeOf(expr)
+	if typ == nil {
+		return false
+	}
+	return types.Identical(typ, types.Universe.Lookup("error").Type())
+}
+
 var slashCommentPrefixRe = regexp.MustCompile("^// ?")
 
 func trimCommentPrefix(c string) string {
@@ -87,80 +180,562 @@ func cleanupComment(commentGroups ...*ast.CommentGroup) string {
 	return strings.Join(commentParts, " ")
 }
 
-func paramToDetail(pkg *packages.Package, param *ast.Field) (*Definition, error) {
-	paramType := exprToType(pkg, param.Type)
-	d := &Definition{
-		Type:        paramType,
-		Properties:  map[string]*Definition{},
-		Description: cleanupComment(param.Doc, param.Comment),
+func paramToDetail(pkg *packages.Package, param *ast.Field, seen map[types.Type]*Definition, defs map[string]*Definition) (*Definition, bool, error) {
+	typ := pkg.TypesInfo.TypeOf(param.Type)
+	d, err := typeToDefinition(pkg, typ, param.Type, seen, defs)
+	if err != nil {
+		return nil, false, err
 	}
+	// Go parameters can't actually carry a struct tag, but paramToDetail
+	// shares this logic with struct fields (via typeToDefinition) so that a
+	// future tag on either path is handled the same way.
+	var ft fieldTags
 	if param.Tag != nil {
-		enumOptions, err := parseEnumTag(param.Tag.Value)
+		ft, err = parseFieldTags(param.Tag.Value)
 		if err != nil {
-			return nil, fmt.Errorf("issue parsing 'enum' field tag: %w", err)
+			return nil, false, fmt.Errorf("issue parsing struct tag: %w", err)
+		}
+	}
+	if ft.description != "" {
+		d.Description = ft.description
+	} else {
+		d.Description = cleanupComment(param.Doc, param.Comment)
+	}
+	if ft.enum != nil {
+		d.Enum = ft.enum
+	}
+	applyFieldTags(d, ft)
+	_, isPointer := typ.(*types.Pointer)
+	return d, isRequired(ft, isPointer), nil
+}
+
+// typeToDefinition expands a go/types.Type into a Definition tree. expr, when
+// available, is the AST expression the type was referenced through; it is
+// used to recover doc comments and struct tags that aren't carried by
+// go/types. seen and defs together implement cycle detection and dedup:
+// once a named type has been expanded, later references to it become a
+// "$ref" into defs instead of being expanded again.
+func typeToDefinition(pkg *packages.Package, typ types.Type, expr ast.Expr, seen map[types.Type]*Definition, defs map[string]*Definition) (*Definition, error) {
+	if typ == nil {
+		return &Definition{Type: Null}, nil
+	}
+
+	if d, ok := seen[typ]; ok {
+		if named, ok := typ.(*types.Named); ok {
+			defs[named.Obj().Name()] = d
+			return &Definition{Ref: "#/$defs/" + named.Obj().Name()}, nil
 		}
-		d.Enum = enumOptions
+		return d, nil
+	}
+
+	if d, ok := formatOverride(typ); ok {
+		return d, nil
 	}
 
-	if paramType == "object" {
-		var err error
-		var st *ast.StructType
-		switch pt := param.Type.(type) {
-		case *ast.StructType:
-			st = pt
-		case *ast.Ident:
-			st, _ = findStructTypeFromIdent(pt)
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		return &Definition{Type: basicTypeToDataType(t), Format: basicFormat(t)}, nil
+	case *types.Slice:
+		if b, ok := t.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return &Definition{Type: String, Format: "byte"}, nil
 		}
-		for _, f := range st.Fields.List {
-			d.Properties[identsToName(f.Names)], err = paramToDetail(pkg, f)
+		items, err := typeToDefinition(pkg, t.Elem(), nil, seen, defs)
+		if err != nil {
+			return nil, err
+		}
+		return &Definition{Type: Array, Items: items}, nil
+	case *types.Array:
+		items, err := typeToDefinition(pkg, t.Elem(), nil, seen, defs)
+		if err != nil {
+			return nil, err
+		}
+		return &Definition{Type: Array, Items: items}, nil
+	case *types.Map:
+		additional, err := typeToDefinition(pkg, t.Elem(), nil, seen, defs)
+		if err != nil {
+			return nil, err
+		}
+		return &Definition{Type: Object, AdditionalProperties: additional}, nil
+	case *types.Interface:
+		if t.NumMethods() == 0 {
+			// An empty interface accepts any shape, so it is left untyped
+			// rather than degraded to Null.
+			return &Definition{Nullable: true}, nil
+		}
+		impls := findImplementations(pkg, t)
+		if len(impls) == 0 {
+			return &Definition{Nullable: true}, nil
+		}
+		oneOf := make([]*Definition, 0, len(impls))
+		for _, named := range impls {
+			implDef, err := typeToDefinition(pkg, named, nil, seen, defs)
 			if err != nil {
 				return nil, err
 			}
+			oneOf = append(oneOf, implDef)
 		}
-	}
-	if paramType == "array" {
-		d.Items = &Definition{
-			Type: exprToType(pkg, param.Type.(*ast.ArrayType).Elt),
+		return &Definition{OneOf: oneOf, Nullable: true}, nil
+	case *types.Pointer:
+		inner, err := typeToDefinition(pkg, t.Elem(), unwrapStar(expr), seen, defs)
+		if err != nil {
+			return nil, err
+		}
+		d := *inner
+		d.Nullable = true
+		return &d, nil
+	case *types.Struct:
+		d := &Definition{Type: Object, Properties: map[string]*Definition{}}
+		seen[typ] = d
+		if named, ok := typ.(*types.Named); ok {
+			d.typeKey = named.Obj().Name()
+		}
+
+		st, _ := structASTFor(pkg, expr, typ)
+		var embeds []*Definition
+		for i := 0; i < t.NumFields(); i++ {
+			f := t.Field(i)
+			if !f.Exported() {
+				continue
+			}
+			var fieldExpr ast.Expr
+			var doc, comment *ast.CommentGroup
+			var tag *ast.BasicLit
+			if st != nil {
+				if af, ok := astFieldByName(st, f.Name()); ok {
+					fieldExpr = af.Type
+					doc, comment, tag = af.Doc, af.Comment, af.Tag
+				}
+			}
+
+			var ft fieldTags
+			var err error
+			if tag != nil {
+				ft, err = parseFieldTags(tag.Value)
+				if err != nil {
+					return nil, fmt.Errorf("issue parsing struct tag on field %q: %w", f.Name(), err)
+				}
+			}
+			if ft.skip {
+				continue
+			}
+
+			// encoding/json flattens an embedded field's exported fields
+			// into the parent unless the field itself carries a json tag
+			// name, so its schema is promoted into d's own properties
+			// rather than nested under the embedded type's name.
+			if f.Anonymous() && ft.name == "" {
+				if embDef, ok := embeddedStructDef(pkg, f.Type(), fieldExpr, seen, defs); ok {
+					embeds = append(embeds, embDef)
+					continue
+				}
+			}
+
+			fd, err := typeToDefinition(pkg, f.Type(), fieldExpr, seen, defs)
+			if err != nil {
+				return nil, err
+			}
+			if ft.description != "" {
+				fd.Description = ft.description
+			} else if doc != nil || comment != nil {
+				fd.Description = cleanupComment(doc, comment)
+			}
+			if ft.enum != nil {
+				fd.Enum = ft.enum
+			}
+			applyFieldTags(fd, ft)
+
+			name := f.Name()
+			if ft.name != "" {
+				name = ft.name
+			}
+			_, isPointer := f.Type().(*types.Pointer)
+			d.Properties[name] = fd
+			if isRequired(ft, isPointer) {
+				d.Required = append(d.Required, name)
+			}
+		}
+		// Embedded fields are merged last, and only into names the struct's
+		// own fields didn't already claim, since a shallower field always
+		// shadows one promoted from an embedded type.
+		for _, embDef := range embeds {
+			for name, prop := range embDef.Properties {
+				if _, exists := d.Properties[name]; exists {
+					continue
+				}
+				d.Properties[name] = prop
+				if containsStr(embDef.Required, name) {
+					d.Required = append(d.Required, name)
+				}
+			}
 		}
+		return d, nil
+	default:
+		return &Definition{Type: Null}, nil
 	}
-	return d, nil
 }
 
-func findStructTypeFromIdent(ident *ast.Ident) (*ast.StructType, bool) {
-	// Check if the ident has an associated object (it should if the parser had type info).
-	if ident.Obj == nil {
+// embeddedStructDef returns the Definition for an embedded field's type,
+// for the caller to flatten into its own Properties, if that type (after
+// unwrapping one level of pointer) is itself a struct. expr, if the
+// embedded field's type was itself resolved from seen, is unused; seen and
+// defs are threaded through like any other typeToDefinition call.
+func embeddedStructDef(pkg *packages.Package, typ types.Type, expr ast.Expr, seen map[types.Type]*Definition, defs map[string]*Definition) (*Definition, bool) {
+	elem := typ
+	if p, ok := elem.(*types.Pointer); ok {
+		elem = p.Elem()
+		expr = unwrapStar(expr)
+	}
+	if _, ok := elem.Underlying().(*types.Struct); !ok {
 		return nil, false
 	}
+	if cached, ok := seen[elem]; ok {
+		return cached, true
+	}
+	d, err := typeToDefinition(pkg, elem, expr, seen, defs)
+	if err != nil || d.Ref != "" {
+		return nil, false
+	}
+	return d, true
+}
 
-	// Check if the declaration of the object is a type specification.
-	typeSpec, ok := ident.Obj.Decl.(*ast.TypeSpec)
-	if !ok {
+// containsStr reports whether s contains v.
+func containsStr(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOverride reports the Definition for named types that are better
+// represented as a formatted scalar than expanded field-by-field, such as
+// time.Time or uuid.UUID.
+func formatOverride(typ types.Type) (*Definition, bool) {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
 		return nil, false
 	}
+	switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+	case "time.Time":
+		return &Definition{Type: String, Format: "date-time"}, true
+	case "github.com/google/uuid.UUID":
+		return &Definition{Type: String, Format: "uuid"}, true
+	default:
+		return nil, false
+	}
+}
+
+// basicFormat reports the OpenAPI format, if any, that refines a basic
+// type's width beyond its DataType (e.g. distinguishing int32 from int64).
+func basicFormat(basic *types.Basic) string {
+	switch basic.Kind() {
+	case types.Int64, types.Uint64:
+		return "int64"
+	case types.Int32, types.Uint32:
+		return "int32"
+	default:
+		return ""
+	}
+}
+
+// findImplementations scans every package reachable from pkg for named
+// types implementing iface, so that an interface-typed parameter can be
+// rendered as a "oneOf" of its known implementations instead of an opaque
+// blob.
+func findImplementations(pkg *packages.Package, iface *types.Interface) []*types.Named {
+	var impls []*types.Named
+	seen := map[string]bool{}
+	for _, p := range reachablePackages(pkg) {
+		if p.Types == nil {
+			continue
+		}
+		scope := p.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			if _, isIface := named.Underlying().(*types.Interface); isIface {
+				continue
+			}
+			if !types.Implements(named, iface) && !types.Implements(types.NewPointer(named), iface) {
+				continue
+			}
+			key := obj.Pkg().Path() + "." + obj.Name()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			impls = append(impls, named)
+		}
+	}
+	return impls
+}
 
-	// Finally, assert that the type specification is indeed a struct type.
-	structType, ok := typeSpec.Type.(*ast.StructType)
+// unwrapStar returns the operand of a pointer type expression, so that doc
+// comments and tags can still be recovered after a *types.Pointer has been
+// unwrapped.
+func unwrapStar(expr ast.Expr) ast.Expr {
+	if se, ok := expr.(*ast.StarExpr); ok {
+		return se.X
+	}
+	return expr
+}
+
+// structASTFor locates the *ast.StructType backing typ, preferring expr (a
+// struct literal or a local identifier) and falling back to a search across
+// every file reachable from pkg. The fallback is what allows named types
+// declared in another file of the same package, or in an imported package
+// whose syntax was loaded, to be expanded instead of silently degrading.
+func structASTFor(pkg *packages.Package, expr ast.Expr, typ types.Type) (*ast.StructType, bool) {
+	switch e := expr.(type) {
+	case *ast.StructType:
+		return e, true
+	case *ast.Ident:
+		if e.Obj != nil {
+			if ts, ok := e.Obj.Decl.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					return st, true
+				}
+			}
+		}
+	}
+	named, ok := typ.(*types.Named)
 	if !ok {
 		return nil, false
 	}
+	return findStructDecl(pkg, named)
+}
 
-	return structType, true
+func findStructDecl(pkg *packages.Package, named *types.Named) (*ast.StructType, bool) {
+	obj := named.Obj()
+	for _, p := range reachablePackages(pkg) {
+		if obj.Pkg() != nil && p.Types != obj.Pkg() {
+			continue
+		}
+		for _, file := range p.Syntax {
+			for _, decl := range file.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || ts.Name.Name != obj.Name() {
+						continue
+					}
+					if st, ok := ts.Type.(*ast.StructType); ok {
+						return st, true
+					}
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// reachablePackages returns pkg and every package reachable through its
+// Imports, so that types declared outside the initially loaded package can
+// still be resolved when their syntax was loaded (NeedDeps|NeedImports).
+func reachablePackages(pkg *packages.Package) []*packages.Package {
+	seen := map[*packages.Package]bool{}
+	var all []*packages.Package
+	var walk func(p *packages.Package)
+	walk = func(p *packages.Package) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		all = append(all, p)
+		for _, imp := range p.Imports {
+			walk(imp)
+		}
+	}
+	walk(pkg)
+	return all
+}
+
+func astFieldByName(st *ast.StructType, name string) (*ast.Field, bool) {
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			// Embedded field; its name is the type name, matching how
+			// go/types names the corresponding Var.
+			if embeddedName(f.Type) == name {
+				return f, true
+			}
+			continue
+		}
+		for _, n := range f.Names {
+			if n.Name == name {
+				return f, true
+			}
+		}
+	}
+	return nil, false
 }
 
-func parseEnumTag(tag string) ([]string, error) {
-	tag = strings.Trim(tag, "`")
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	default:
+		return ""
+	}
+}
+
+// fieldTags holds the schema customizations recognized on a struct field's
+// tag, merged from its json, enum, description, jsonschema and validate
+// keys.
+type fieldTags struct {
+	name        string
+	skip        bool
+	required    *bool // nil when no tag expresses an opinion
+	description string
+	enum        []string
+	format      string
+	pattern     string
+	minimum     *float64
+	maximum     *float64
+	minLength   *int
+	maxLength   *int
+	defaultVal  any
+}
+
+// isRequired reports whether a field should be listed in its parent's
+// Required slice: required by default, unless a tag opted it out (e.g.
+// json:",omitempty") or the field is a pointer (encoding/json happily
+// accepts a missing pointer field as its nil zero value), and regardless
+// of either forced on by an explicit validate:"required" tag.
+func isRequired(ft fieldTags, isPointer bool) bool {
+	if ft.required != nil {
+		return *ft.required
+	}
+	return !isPointer
+}
+
+// parseFieldTags reads the json, enum, description, jsonschema and validate
+// tag keys off of a raw struct tag string (e.g. `json:"name,omitempty"
+// validate:"required,email"`).
+func parseFieldTags(rawTag string) (fieldTags, error) {
+	var ft fieldTags
+	tag := strings.Trim(rawTag, "`")
 	tags, err := structtag.Parse(tag)
 	if err != nil {
-		return nil, fmt.Errorf("parse('%v'): %w", tag, err)
+		return ft, fmt.Errorf("parse('%v'): %w", tag, err)
 	}
-	value, err := tags.Get("enum")
-	if err != nil {
-		return nil, nil
+
+	if jsonTag, err := tags.Get("json"); err == nil {
+		switch jsonTag.Name {
+		case "-":
+			ft.skip = true
+		case "":
+		default:
+			ft.name = jsonTag.Name
+		}
+		for _, opt := range jsonTag.Options {
+			if opt == "omitempty" {
+				notRequired := false
+				ft.required = &notRequired
+			}
+		}
+	}
+
+	if enumTag, err := tags.Get("enum"); err == nil {
+		ft.enum = append([]string{enumTag.Name}, enumTag.Options...)
+	}
+
+	// description is read straight off the raw tag via reflect.StructTag
+	// rather than through structtag's Name/Options split, since a
+	// description is free-form text that may itself contain commas (e.g.
+	// `description:"Hello, world"`) and structtag has no notion of
+	// re-escaping those back into a single string.
+	if desc, ok := reflect.StructTag(tag).Lookup("description"); ok {
+		ft.description = desc
+	}
+
+	if jsonschemaTag, err := tags.Get("jsonschema"); err == nil {
+		for _, kv := range append([]string{jsonschemaTag.Name}, jsonschemaTag.Options...) {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "format":
+				ft.format = value
+			case "pattern":
+				ft.pattern = value
+			case "default":
+				ft.defaultVal = value
+			case "minimum":
+				if f, err := strconv.ParseFloat(value, 64); err == nil {
+					ft.minimum = &f
+				}
+			case "maximum":
+				if f, err := strconv.ParseFloat(value, 64); err == nil {
+					ft.maximum = &f
+				}
+			case "minLength":
+				if n, err := strconv.Atoi(value); err == nil {
+					ft.minLength = &n
+				}
+			case "maxLength":
+				if n, err := strconv.Atoi(value); err == nil {
+					ft.maxLength = &n
+				}
+			}
+		}
+	}
+
+	if validateTag, err := tags.Get("validate"); err == nil {
+		for _, rule := range append([]string{validateTag.Name}, validateTag.Options...) {
+			switch {
+			case rule == "required":
+				required := true
+				ft.required = &required
+			case rule == "email":
+				ft.format = "email"
+			case strings.HasPrefix(rule, "oneof="):
+				ft.enum = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			}
+		}
+	}
+
+	return ft, nil
+}
+
+// applyFieldTags copies the schema-level customizations from a parsed tag
+// onto d, leaving fields the tag didn't mention untouched.
+func applyFieldTags(d *Definition, ft fieldTags) {
+	if ft.format != "" {
+		d.Format = ft.format
+	}
+	if ft.pattern != "" {
+		d.Pattern = ft.pattern
+	}
+	if ft.minimum != nil {
+		d.Minimum = ft.minimum
+	}
+	if ft.maximum != nil {
+		d.Maximum = ft.maximum
+	}
+	if ft.minLength != nil {
+		d.MinLength = ft.minLength
+	}
+	if ft.maxLength != nil {
+		d.MaxLength = ft.maxLength
+	}
+	if ft.defaultVal != nil {
+		d.Default = ft.defaultVal
 	}
-	var options []string
-	options = append(options, value.Name)
-	options = append(options, value.Options...)
-	return options, nil
 }
 
 func identsToName(idents []*ast.Ident) string {
@@ -193,36 +768,3 @@ func findFunctionFile(f *ast.File, funcName string) (*ast.FuncDecl, bool) {
 	}
 	return nil, false
 }
-
-var goTypesToDataType = map[string]DataType{
-	"int":    Integer,
-	"int32":  Integer,
-	"int64":  Integer,
-	"string": String,
-	"float":  Number,
-	"bool":   Boolean,
-}
-
-// exprToType takes an expression and returns its string representation.
-func exprToType(pkg *packages.Package, expr ast.Expr) DataType {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		typ := goTypesToDataType[t.Name]
-		ut := pkg.TypesInfo.Types[t].Type.Underlying()
-		switch ut.(type) {
-		case *types.Struct:
-			return Object
-		}
-		return typ
-	case *ast.ArrayType:
-		return Array
-	case *ast.StarExpr:
-		return exprToType(pkg, t.X)
-	case *ast.StructType:
-		return Object
-	// Add more cases as needed for other types.
-	default:
-		fmt.Printf("uhandled type %T\n", t)
-		return Null
-	}
-}
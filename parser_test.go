@@ -0,0 +1,122 @@
+package go2oapi_test
+
+import (
+	"testing"
+
+	"github.com/tmc/go2oapi"
+)
+
+func TestParseFunctionMultiValueReturns(t *testing.T) {
+	details, err := go2oapi.ParseFunction("testdata/sample-returns", "MultiReturn")
+	if err != nil {
+		t.Fatalf("ParseFunction: %v", err)
+	}
+
+	if !details.ReturnsError {
+		t.Error("ReturnsError = false, want true for a trailing error result")
+	}
+	if details.Returns == nil {
+		t.Fatal("Returns is nil, want an object keyed by the two non-error results")
+	}
+	if details.Returns.Type != go2oapi.Object {
+		t.Errorf("Returns.Type = %q, want %q", details.Returns.Type, go2oapi.Object)
+	}
+
+	for _, name := range []string{"r0", "r1"} {
+		if _, ok := details.Returns.Properties[name]; !ok {
+			t.Errorf("Returns.Properties missing %q: %+v", name, details.Returns.Properties)
+		}
+	}
+	if got := details.Returns.Properties["r0"].Type; got != go2oapi.String {
+		t.Errorf("r0.Type = %q, want %q", got, go2oapi.String)
+	}
+	if got := details.Returns.Properties["r1"].Type; got != go2oapi.Integer {
+		t.Errorf("r1.Type = %q, want %q", got, go2oapi.Integer)
+	}
+}
+
+// TestParseFunctionPointerNotRequired covers that a pointer parameter or
+// field defaults to optional, since encoding/json accepts a missing pointer
+// as its nil zero value, unless an explicit validate:"required" tag says
+// otherwise.
+func TestParseFunctionPointerNotRequired(t *testing.T) {
+	details, err := go2oapi.ParseFunction("testdata/sample-pointer", "F")
+	if err != nil {
+		t.Fatalf("ParseFunction: %v", err)
+	}
+
+	contains := func(required []string, name string) bool {
+		for _, r := range required {
+			if r == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !contains(details.Parameters.Required, "w") {
+		t.Errorf("Required = %v, want \"w\" (non-pointer) present", details.Parameters.Required)
+	}
+	if contains(details.Parameters.Required, "extra") {
+		t.Errorf("Required = %v, want \"extra\" (pointer) absent", details.Parameters.Required)
+	}
+	if !details.Parameters.Properties["extra"].Nullable {
+		t.Error("extra.Nullable = false, want true for a pointer parameter")
+	}
+}
+
+// TestParseFunctionPointerFieldRequired covers the same pointer-not-required
+// default for a struct field, and that validate:"required" still overrides
+// it.
+func TestParseFunctionPointerFieldRequired(t *testing.T) {
+	details, err := go2oapi.ParseFunction("testdata/sample-pointer", "G")
+	if err != nil {
+		t.Fatalf("ParseFunction: %v", err)
+	}
+	holderDef, ok := details.Parameters.Properties["h"]
+	if !ok {
+		t.Fatalf("Parameters missing property \"h\": %+v", details.Parameters)
+	}
+
+	contains := func(required []string, name string) bool {
+		for _, r := range required {
+			if r == name {
+				return true
+			}
+		}
+		return false
+	}
+	if !contains(holderDef.Required, "Primary") {
+		t.Errorf("Holder.Required = %v, want \"Primary\" (non-pointer) present", holderDef.Required)
+	}
+	if contains(holderDef.Required, "Backup") {
+		t.Errorf("Holder.Required = %v, want \"Backup\" (plain pointer) absent", holderDef.Required)
+	}
+	if !contains(holderDef.Required, "Forced") {
+		t.Errorf("Holder.Required = %v, want \"Forced\" (validate:required pointer) present", holderDef.Required)
+	}
+}
+
+// TestParseFunctionDescriptionAndLengthTags covers a description struct tag
+// value containing a comma (which must survive intact, space and all) and
+// the jsonschema minLength/maxLength keys.
+func TestParseFunctionDescriptionAndLengthTags(t *testing.T) {
+	details, err := go2oapi.ParseFunction("testdata/sample-tags", "F")
+	if err != nil {
+		t.Fatalf("ParseFunction: %v", err)
+	}
+
+	name := details.Parameters.Properties["w"].Properties["Name"]
+	if name == nil {
+		t.Fatalf("Widget.Name not found: %+v", details.Parameters)
+	}
+	if name.Description != "Hello, world" {
+		t.Errorf("Name.Description = %q, want %q", name.Description, "Hello, world")
+	}
+	if name.MinLength == nil || *name.MinLength != 1 {
+		t.Errorf("Name.MinLength = %v, want 1", name.MinLength)
+	}
+	if name.MaxLength == nil || *name.MaxLength != 10 {
+		t.Errorf("Name.MaxLength = %v, want 10", name.MaxLength)
+	}
+}
@@ -0,0 +1,20 @@
+package go2oapi
+
+// registry holds schemas registered via Register, keyed by function name.
+// Generated "<file>_oapi.go" files (see Generate) populate it from an
+// init(), so that a program can look up a tool's schema without parsing Go
+// source at runtime.
+var registry = map[string]FunctionDetails{}
+
+// Register adds a precomputed schema to the default registry, keyed by
+// name. It is called from files generated by `go2oapi -type=...`; most
+// callers won't need to invoke it directly.
+func Register(name string, details FunctionDetails) {
+	registry[name] = details
+}
+
+// Lookup returns the schema registered under name, if any.
+func Lookup(name string) (FunctionDetails, bool) {
+	details, ok := registry[name]
+	return details, ok
+}
@@ -29,6 +29,8 @@ type NewWidgetFactoryOptions struct {
 	Operational     bool
 }
 
+//go:generate go2oapi -type=NewWidgetFactory
+
 // NewWidgetFactory creates a new widget factory.
 func NewWidgetFactory(factoryInfo NewWidgetFactoryOptions) (string, error) {
 	return "", nil
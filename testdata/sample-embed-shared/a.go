@@ -0,0 +1,18 @@
+package sampleembedshared
+
+// Base is embedded (and flattened) by Widget, and also used directly as a
+// second parameter, to exercise hoisting a type whose first use is hidden
+// inside an embedded field.
+type Base struct {
+	ID string
+}
+
+type Widget struct {
+	Base
+	Color string
+}
+
+// F takes a Widget (embedding Base) and a Base directly.
+func F(w Widget, b Base) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+package sampleoverlay
+
+// Widget is referenced both through a pointer parameter and through a
+// documented struct field, to exercise preserving per-use-site keywords
+// (nullable, description) on a hoisted $ref.
+type Widget struct {
+	Name string
+}
+
+type Holder struct {
+	// Primary is the main widget.
+	Primary Widget
+}
+
+// F takes a *Widget (nullable) and a Holder whose Primary field documents
+// its own Widget.
+func F(w *Widget, h Holder) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package samplepointer
+
+type Widget struct {
+	Name string
+}
+
+type Holder struct {
+	Primary Widget
+	// Backup is optional; callers may omit it entirely.
+	Backup *Widget
+	// Forced is a pointer explicitly opted back into Required.
+	Forced *Widget `validate:"required"`
+}
+
+// F takes a required Widget and an optional *Widget.
+func F(w Widget, extra *Widget) error {
+	return nil
+}
+
+// G takes a Holder, to exercise pointer fields.
+func G(h Holder) error {
+	return nil
+}
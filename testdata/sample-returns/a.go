@@ -0,0 +1,6 @@
+package samplereturns
+
+// MultiReturn returns more than one value alongside an error.
+func MultiReturn(name string) (string, int, error) {
+	return name, 0, nil
+}
@@ -0,0 +1,17 @@
+package sampleshared
+
+// SharedInput is used by more than one function, to exercise
+// cross-function schema deduplication.
+type SharedInput struct {
+	Name string
+}
+
+// FuncA takes a SharedInput.
+func FuncA(in SharedInput) error {
+	return nil
+}
+
+// FuncB also takes a SharedInput.
+func FuncB(in SharedInput) error {
+	return nil
+}
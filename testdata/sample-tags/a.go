@@ -0,0 +1,10 @@
+package sampletags
+
+type Widget struct {
+	Name string `description:"Hello, world" jsonschema:"minLength=1,maxLength=10"`
+}
+
+// F takes a Widget, to exercise the description and jsonschema struct tags.
+func F(w Widget) error {
+	return nil
+}
@@ -1,7 +1,6 @@
 package go2oapi
 
 import (
-	"go/ast"
 	"go/types"
 )
 
@@ -26,6 +25,15 @@ type FunctionDetails struct {
 	Description string `json:"description"`
 	// Parameters of the function.
 	Parameters *Definition `json:"parameters"`
+	// Returns describes the function's non-error return values: a single
+	// Definition when there is exactly one unnamed value, or an object
+	// keyed by name (or positional "r0", "r1", ... when unnamed) when
+	// there is more than one.
+	Returns *Definition `json:"returns,omitempty"`
+	// ReturnsError reports whether the function has a result of type
+	// error (by Go convention the trailing one). Error results are not
+	// modeled in Returns, whatever their position.
+	ReturnsError bool `json:"returnsError,omitempty"`
 }
 
 // Definition holds the name and type of a function parameter.
@@ -43,28 +51,52 @@ type Definition struct {
 	Required []string `json:"required,omitempty"`
 	// Items specifies which data type an array contains, if the schema type is Array.
 	Items *Definition `json:"items,omitempty"`
-}
-
-func exprToType(info *types.Info, expr ast.Expr) DataType {
-	typ := info.TypeOf(expr)
-	if typ == nil {
-		return Null // or some error handling
-	}
+	// AdditionalProperties describes the value type for map-shaped objects
+	// (e.g. map[string]T), mirroring JSON Schema's keyword of the same name.
+	AdditionalProperties *Definition `json:"additionalProperties,omitempty"`
+	// Ref points at an entry in the Parameters root's Defs, used in place of
+	// inlining a type that has already been expanded elsewhere in the
+	// function's schemas (shared or self-referential named types). This
+	// holds for a Ref inside Returns too: Returns carries no Defs of its own.
+	Ref string `json:"$ref,omitempty"`
+	// Defs holds named type definitions referenced via Ref elsewhere in the
+	// tree. Only populated on the root Definition of a function's
+	// Parameters.
+	Defs map[string]*Definition `json:"$defs,omitempty"`
+	// Format refines Type with a well-known JSON Schema/OpenAPI format, e.g.
+	// "date-time", "byte", "uuid", "int32" or "int64".
+	Format string `json:"format,omitempty"`
+	// Nullable indicates the value may be null, as is the case for Go
+	// pointer and interface types.
+	Nullable bool `json:"nullable,omitempty"`
+	// OneOf lists the possible schemas for a value, used for interface
+	// types whose implementations were discovered in the loaded packages.
+	OneOf []*Definition `json:"oneOf,omitempty"`
+	// AnyOf lists schemas of which at least one must match.
+	AnyOf []*Definition `json:"anyOf,omitempty"`
+	// AllOf lists schemas which must all match.
+	AllOf []*Definition `json:"allOf,omitempty"`
+	// Minimum and Maximum bound a numeric value.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// MinLength and MaxLength bound a string value's length.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Pattern is a regular expression a string value must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Default is the value assumed when none is supplied.
+	Default any `json:"default,omitempty"`
 
-	switch typ := typ.Underlying().(type) {
-	case *types.Basic:
-		return basicTypeToDataType(typ)
-	case *types.Array, *types.Slice:
-		return Array
-	case *types.Struct:
-		return Object
-	case *types.Pointer:
-		return exprToType(info, &ast.Ident{Name: typ.Elem().String()})
-	default:
-		return Null
-	}
+	// typeKey, when non-empty, is the name of the Go named type this
+	// Definition was expanded from. It is unexported (so never serialized)
+	// and exists solely so BuildOpenAPI can recognize the same named type
+	// reused across different functions and hoist it into
+	// components/schemas instead of inlining it once per function.
+	typeKey string
 }
 
+// basicTypeToDataType maps a go/types basic kind to its JSON Schema
+// equivalent.
 func basicTypeToDataType(basic *types.Basic) DataType {
 	switch basic.Kind() {
 	case types.Bool: